@@ -36,14 +36,120 @@ type DeadlineConn struct {
 	readSetAt               time.Time
 	writeDeadline           time.Duration // sets the write deadline on a connection.
 	writeSetAt              time.Time
+	minReadRate             int64 // minimum acceptable read throughput, in bytes/sec.
+	minWriteRate            int64 // minimum acceptable write throughput, in bytes/sec.
 	abortReads, abortWrites atomic.Bool // A deadline was set to indicate caller wanted the conn to time out.
 	mu                      sync.Mutex
+	keepAlive               *keepAlive // non-nil once WithKeepAlive has been called.
+	hooks                   Hooks
+	stats                   stats
+	interrupt               func() // non-nil once WithInterrupter has been called.
+}
+
+// Direction identifies which side of the connection an OnBytes hook fired for.
+type Direction int
+
+const (
+	// DirRead is passed to Hooks.OnBytes for data read from the connection.
+	DirRead Direction = iota
+	// DirWrite is passed to Hooks.OnBytes for data written to the connection.
+	DirWrite
+)
+
+// Hooks lets callers observe per-connection I/O stall events, e.g. to feed a
+// Prometheus counter, without needing to wrap the conn again at a higher
+// layer. Any field left nil is simply not invoked.
+type Hooks struct {
+	OnReadTimeout  func()
+	OnWriteTimeout func()
+	OnAbort        func()
+	OnBytes        func(n int, dir Direction)
+}
+
+// stats holds the running counters backing Stats().
+type stats struct {
+	bytesRead      atomic.Int64
+	bytesWritten   atomic.Int64
+	deadlineResets atomic.Int64
+	aborts         atomic.Int64
+}
+
+// Stats is a point-in-time snapshot of a DeadlineConn's I/O counters.
+type Stats struct {
+	BytesRead      int64
+	BytesWritten   int64
+	DeadlineResets int64
+	Aborts         int64
+}
+
+// Stats returns a snapshot of the connection's accumulated I/O counters.
+func (c *DeadlineConn) Stats() Stats {
+	return Stats{
+		BytesRead:      c.stats.bytesRead.Load(),
+		BytesWritten:   c.stats.bytesWritten.Load(),
+		DeadlineResets: c.stats.deadlineResets.Load(),
+		Aborts:         c.stats.aborts.Load(),
+	}
+}
+
+// Probe is invoked periodically by the keepalive pinger started via WithKeepAlive
+// to detect peers that have gone silently dead. Returning a non-nil error aborts
+// the connection, just as setting a deadline in the past via SetDeadline does.
+type Probe func(net.Conn) error
+
+// keepAlive holds the state of the background pinger goroutine.
+type keepAlive struct {
+	interval time.Duration
+	probe    Probe
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// budgetDeadline returns how long the caller should be given to move n bytes,
+// given a base idle deadline and a minimum acceptable throughput: it is
+// whichever of the two is larger, so that large legitimate transfers on slow
+// links are not spuriously aborted while stalled sockets still time out
+// promptly.
+func budgetDeadline(base time.Duration, minRate int64, n int) time.Duration {
+	if minRate <= 0 || n <= 0 {
+		return base
+	}
+	if budget := time.Duration(n) * time.Second / time.Duration(minRate); budget > base {
+		return budget
+	}
+	return base
+}
+
+// armTimer is the WithInterrupter counterpart to setReadDeadline/
+// setWriteDeadline, used when the caller has told us (via WithInterrupter)
+// that the wrapped conn's SetReadDeadline/SetWriteDeadline cannot be trusted
+// to actually enforce anything. It schedules c.interrupt to fire once the
+// per-call budget deadline elapses, and returns a func that must be called
+// when the guarded Read/Write returns, to disarm it. onTimeout, if non-nil,
+// is invoked from the timer callback itself: interrupting a blocked
+// Read/Write this way never produces a net.Error with Timeout() == true for
+// isTimeout to detect afterwards, so Hooks.OnReadTimeout/OnWriteTimeout must
+// be fired from here instead.
+func (c *DeadlineConn) armTimer(base time.Duration, minRate int64, n int, onTimeout func()) func() {
+	d := budgetDeadline(base, minRate, n)
+	if d <= 0 {
+		return func() {}
+	}
+	c.stats.deadlineResets.Add(1)
+	timer := time.AfterFunc(d, func() {
+		c.abort()
+		if onTimeout != nil {
+			onTimeout()
+		}
+		c.interrupt()
+	})
+	return func() { timer.Stop() }
 }
 
 // Sets read deadline
-func (c *DeadlineConn) setReadDeadline() {
+func (c *DeadlineConn) setReadDeadline(n int) {
 	// Do not set a Read deadline, if upstream wants to cancel all reads.
-	if c.readDeadline <= 0 || c.abortReads.Load() {
+	if (c.readDeadline <= 0 && c.minReadRate <= 0) || c.abortReads.Load() {
 		return
 	}
 
@@ -55,14 +161,16 @@ func (c *DeadlineConn) setReadDeadline() {
 
 	now := time.Now()
 	if now.Sub(c.readSetAt) > updateInterval {
-		c.Conn.SetReadDeadline(now.Add(c.readDeadline + updateInterval))
+		d := budgetDeadline(c.readDeadline, c.minReadRate, n)
+		c.Conn.SetReadDeadline(now.Add(d + updateInterval))
 		c.readSetAt = now
+		c.stats.deadlineResets.Add(1)
 	}
 }
 
-func (c *DeadlineConn) setWriteDeadline() {
+func (c *DeadlineConn) setWriteDeadline(n int) {
 	// Do not set a Write deadline, if upstream wants to cancel all reads.
-	if c.writeDeadline <= 0 || c.abortWrites.Load() {
+	if (c.writeDeadline <= 0 && c.minWriteRate <= 0) || c.abortWrites.Load() {
 		return
 	}
 
@@ -73,8 +181,10 @@ func (c *DeadlineConn) setWriteDeadline() {
 	}
 	now := time.Now()
 	if now.Sub(c.writeSetAt) > updateInterval {
-		c.Conn.SetWriteDeadline(now.Add(c.writeDeadline + updateInterval))
+		d := budgetDeadline(c.writeDeadline, c.minWriteRate, n)
+		c.Conn.SetWriteDeadline(now.Add(d + updateInterval))
 		c.writeSetAt = now
+		c.stats.deadlineResets.Add(1)
 	}
 }
 
@@ -83,8 +193,21 @@ func (c *DeadlineConn) Read(b []byte) (n int, err error) {
 	if c.abortReads.Load() {
 		return 0, context.DeadlineExceeded
 	}
-	c.setReadDeadline()
+	if c.interrupt != nil {
+		defer c.armTimer(c.readDeadline, c.minReadRate, len(b), c.hooks.OnReadTimeout)()
+	} else {
+		c.setReadDeadline(len(b))
+	}
 	n, err = c.Conn.Read(b)
+	if n > 0 {
+		c.stats.bytesRead.Add(int64(n))
+		if c.hooks.OnBytes != nil {
+			c.hooks.OnBytes(n, DirRead)
+		}
+	}
+	if isTimeout(err) && c.hooks.OnReadTimeout != nil {
+		c.hooks.OnReadTimeout()
+	}
 	return n, err
 }
 
@@ -93,11 +216,30 @@ func (c *DeadlineConn) Write(b []byte) (n int, err error) {
 	if c.abortWrites.Load() {
 		return 0, context.DeadlineExceeded
 	}
-	c.setWriteDeadline()
+	if c.interrupt != nil {
+		defer c.armTimer(c.writeDeadline, c.minWriteRate, len(b), c.hooks.OnWriteTimeout)()
+	} else {
+		c.setWriteDeadline(len(b))
+	}
 	n, err = c.Conn.Write(b)
+	if n > 0 {
+		c.stats.bytesWritten.Add(int64(n))
+		if c.hooks.OnBytes != nil {
+			c.hooks.OnBytes(n, DirWrite)
+		}
+	}
+	if isTimeout(err) && c.hooks.OnWriteTimeout != nil {
+		c.hooks.OnWriteTimeout()
+	}
 	return n, err
 }
 
+// isTimeout reports whether err is a network timeout error.
+func isTimeout(err error) bool {
+	ne, ok := err.(net.Error)
+	return ok && ne.Timeout()
+}
+
 // SetDeadline will set the deadline for reads and writes.
 // A zero value for t means I/O operations will not time out.
 func (c *DeadlineConn) SetDeadline(t time.Time) error {
@@ -119,8 +261,7 @@ func (c *DeadlineConn) SetDeadline(t time.Time) error {
 	}
 	// If upstream sets a deadline in the past, assume it wants to abort reads/writes.
 	if time.Until(t) < 0 {
-		c.abortReads.Store(true)
-		c.abortWrites.Store(true)
+		c.abort()
 		return c.Conn.SetDeadline(t)
 	}
 
@@ -166,13 +307,89 @@ func (c *DeadlineConn) SetWriteDeadline(t time.Time) error {
 	return c.Conn.SetWriteDeadline(t)
 }
 
-// Close wraps conn.Close and stops sending deadline updates.
-func (c *DeadlineConn) Close() error {
+// quiesce marks the connection as aborted so subsequent Read/Write calls
+// return context.DeadlineExceeded without touching the underlying conn, with
+// no abort telemetry. Used where reads/writes failing afterwards is the
+// expected, healthy outcome (a plain Close), as opposed to a genuine
+// timeout/probe-failure abort that operators want visibility into.
+func (c *DeadlineConn) quiesce() {
 	c.abortReads.Store(true)
 	c.abortWrites.Store(true)
+}
+
+// abort quiesces the connection and records abort telemetry (Stats().Aborts,
+// Hooks.OnAbort). Only call this from genuine abort paths: an explicit
+// past-deadline SetDeadline, a failed keepalive Probe, or an interrupter-
+// driven deadline — never from a plain Close, which is not a stall signal.
+func (c *DeadlineConn) abort() {
+	wasReadAborted := c.abortReads.Swap(true)
+	wasWriteAborted := c.abortWrites.Swap(true)
+	if !wasReadAborted || !wasWriteAborted {
+		c.stats.aborts.Add(1)
+		if c.hooks.OnAbort != nil {
+			c.hooks.OnAbort()
+		}
+	}
+}
+
+// Close wraps conn.Close and stops sending deadline updates.
+func (c *DeadlineConn) Close() error {
+	c.quiesce()
+	c.mu.Lock()
+	ka := c.keepAlive
+	c.mu.Unlock()
+	if ka != nil {
+		close(ka.stopCh)
+		ka.wg.Wait()
+	}
 	return c.Conn.Close()
 }
 
+// WithKeepAlive starts a background goroutine that invokes probe on the
+// underlying connection every interval, e.g. to send a protocol-specific
+// ping frame. A failed probe aborts the connection so that idle, silently
+// dead peers are detected without waiting for the full read deadline.
+// The pinger is stopped when the connection is closed.
+func (c *DeadlineConn) WithKeepAlive(interval time.Duration, probe Probe) *DeadlineConn {
+	c.mu.Lock()
+	if c.keepAlive != nil {
+		close(c.keepAlive.stopCh)
+		c.mu.Unlock()
+		c.keepAlive.wg.Wait()
+		c.mu.Lock()
+	}
+	ka := &keepAlive{
+		interval: interval,
+		probe:    probe,
+		stopCh:   make(chan struct{}),
+	}
+	c.keepAlive = ka
+	c.mu.Unlock()
+
+	ka.wg.Add(1)
+	go c.pinger(ka)
+	return c
+}
+
+// pinger runs the keepalive probe on a ticker until stopCh is closed or the
+// probe reports a dead connection.
+func (c *DeadlineConn) pinger(ka *keepAlive) {
+	defer ka.wg.Done()
+	t := time.NewTicker(ka.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ka.stopCh:
+			return
+		case <-t.C:
+			if err := ka.probe(c.Conn); err != nil {
+				c.abort()
+				return
+			}
+		}
+	}
+}
+
 // WithReadDeadline sets a new read side net.Conn deadline.
 func (c *DeadlineConn) WithReadDeadline(d time.Duration) *DeadlineConn {
 	c.readDeadline = d
@@ -185,6 +402,49 @@ func (c *DeadlineConn) WithWriteDeadline(d time.Duration) *DeadlineConn {
 	return c
 }
 
+// WithMinReadRate requires Read calls to make at least bytesPerSec bytes/sec
+// of progress, extending the read deadline for large transfers beyond the
+// base deadline set by WithReadDeadline so that slow-but-live links are not
+// spuriously aborted while idle/stalled sockets still time out promptly.
+func (c *DeadlineConn) WithMinReadRate(bytesPerSec int) *DeadlineConn {
+	c.minReadRate = int64(bytesPerSec)
+	return c
+}
+
+// WithMinWriteRate requires Write calls to make at least bytesPerSec
+// bytes/sec of progress, extending the write deadline for large transfers
+// beyond the base deadline set by WithWriteDeadline so that slow-but-live
+// links are not spuriously aborted while idle/stalled sockets still time out
+// promptly.
+func (c *DeadlineConn) WithMinWriteRate(bytesPerSec int) *DeadlineConn {
+	c.minWriteRate = int64(bytesPerSec)
+	return c
+}
+
+// WithHooks attaches Hooks to be invoked on read/write timeouts, aborts, and
+// byte transfers, letting callers plumb per-connection I/O stall telemetry
+// into metrics such as Prometheus without re-wrapping the conn.
+func (c *DeadlineConn) WithHooks(h Hooks) *DeadlineConn {
+	c.hooks = h
+	return c
+}
+
+// WithInterrupter opts the connection out of relying on the wrapped conn's
+// SetReadDeadline/SetWriteDeadline and switches to goroutine-based deadline
+// enforcement instead: a timer is armed around every outstanding Read/Write
+// that calls fn once the deadline elapses. There is no way to detect a
+// transport whose SetReadDeadline/SetWriteDeadline silently no-op, so
+// callers must opt in explicitly for such transports — e.g. WebSocket
+// message-framed conns, stdio pipes used for RPC, or some QUIC stream
+// wrappers. If fn is nil, the conn is closed.
+func (c *DeadlineConn) WithInterrupter(fn func()) *DeadlineConn {
+	if fn == nil {
+		fn = func() { c.Conn.Close() }
+	}
+	c.interrupt = fn
+	return c
+}
+
 // New - creates a new connection object wrapping net.Conn with deadlines.
 func New(c net.Conn) *DeadlineConn {
 	return &DeadlineConn{
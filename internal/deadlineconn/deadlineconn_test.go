@@ -0,0 +1,215 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package deadlineconn
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// throttledWrite writes b to conn in small chunks, sleeping between each
+// chunk so the effective throughput is roughly bytesPerSec.
+func throttledWrite(conn net.Conn, b []byte, bytesPerSec int) {
+	const chunk = 16
+	perChunk := time.Second * chunk / time.Duration(bytesPerSec)
+	for len(b) > 0 {
+		n := chunk
+		if n > len(b) {
+			n = len(b)
+		}
+		conn.Write(b[:n])
+		b = b[n:]
+		if len(b) > 0 {
+			time.Sleep(perChunk)
+		}
+	}
+}
+
+func TestMinReadRateAllowsSlowButLiveLink(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	// 1KB at >= 200 bytes/sec should fit within the ~5s deadline (1024/200)
+	// the min-read-rate budget grants it, even though the base idle deadline
+	// is much shorter.
+	dc := New(server).WithReadDeadline(100 * time.Millisecond).WithMinReadRate(200)
+	defer dc.Close()
+
+	payload := make([]byte, 1024)
+	go throttledWrite(client, payload, 200)
+
+	buf := make([]byte, len(payload))
+	if _, err := readFull(dc, buf); err != nil {
+		t.Fatalf("unexpected error reading from a slow-but-live link: %v", err)
+	}
+}
+
+func TestMinReadRateStillTimesOutOnStall(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	dc := New(server).WithReadDeadline(50 * time.Millisecond).WithMinReadRate(1 << 20)
+	defer dc.Close()
+
+	buf := make([]byte, 16)
+	_, err := dc.Read(buf)
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, net.ErrClosed) {
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			t.Fatalf("expected a timeout on a stalled link, got: %v", err)
+		}
+	}
+}
+
+func TestKeepAliveFailedProbeAborts(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	probeErr := errors.New("peer is dead")
+	dc := New(server).WithKeepAlive(5*time.Millisecond, func(net.Conn) error {
+		return probeErr
+	})
+	defer dc.Close()
+
+	deadline := time.After(time.Second)
+	for !dc.abortReads.Load() {
+		select {
+		case <-deadline:
+			t.Fatal("keepalive probe failure never aborted the connection")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if _, err := dc.Read(make([]byte, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Read to report context.DeadlineExceeded after a failed probe, got: %v", err)
+	}
+	if _, err := dc.Write([]byte("x")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Write to report context.DeadlineExceeded after a failed probe, got: %v", err)
+	}
+}
+
+func TestKeepAliveStopsOnClose(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	var probes atomic.Int64
+	dc := New(server).WithKeepAlive(2*time.Millisecond, func(net.Conn) error {
+		probes.Add(1)
+		return nil
+	})
+
+	time.Sleep(20 * time.Millisecond) // let a few probes run.
+	dc.Close()
+
+	after := probes.Load()
+	time.Sleep(20 * time.Millisecond)
+	if got := probes.Load(); got != after {
+		t.Fatalf("pinger kept probing after Close: %d probes before, %d after", after, got)
+	}
+}
+
+func TestHooksAndStats(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	var reads, aborts, bytesIn atomic.Int64
+	dc := New(server).WithReadDeadline(50 * time.Millisecond).WithHooks(Hooks{
+		OnReadTimeout: func() { reads.Add(1) },
+		OnAbort:       func() { aborts.Add(1) },
+		OnBytes: func(n int, dir Direction) {
+			if dir == DirRead {
+				bytesIn.Add(int64(n))
+			}
+		},
+	})
+
+	go client.Write([]byte("hello"))
+	buf := make([]byte, 5)
+	if _, err := readFull(dc, buf); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if got := dc.Stats().BytesRead; got != 5 {
+		t.Fatalf("Stats().BytesRead = %d, want 5", got)
+	}
+	if got := bytesIn.Load(); got != 5 {
+		t.Fatalf("OnBytes reported %d read bytes, want 5", got)
+	}
+
+	// A stalled read should time out and fire OnReadTimeout.
+	if _, err := dc.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expected a timeout reading from a stalled link")
+	}
+	if reads.Load() == 0 {
+		t.Fatal("OnReadTimeout was never invoked")
+	}
+
+	// A plain Close must not record abort telemetry.
+	dc.Close()
+	if got := dc.Stats().Aborts; got != 0 {
+		t.Fatalf("Stats().Aborts = %d after a plain Close, want 0", got)
+	}
+	if aborts.Load() != 0 {
+		t.Fatal("OnAbort fired on a plain Close")
+	}
+}
+
+func TestInterrupterFiresOnDeadline(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	var timeouts atomic.Int64
+	interrupted := make(chan struct{})
+	dc := New(server).WithReadDeadline(20 * time.Millisecond).
+		WithHooks(Hooks{OnReadTimeout: func() { timeouts.Add(1) }}).
+		WithInterrupter(func() {
+			close(interrupted)
+			server.Close()
+		})
+
+	start := time.Now()
+	if _, err := dc.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expected Read to fail once the interrupter fires")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("Read returned before the deadline elapsed: %v", elapsed)
+	}
+
+	select {
+	case <-interrupted:
+	default:
+		t.Fatal("interrupter was never invoked")
+	}
+	if timeouts.Load() == 0 {
+		t.Fatal("OnReadTimeout was never invoked for an interrupter-driven timeout")
+	}
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package deadlineconn
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Options configures how Listener and DialContext wrap accepted/dialed
+// connections in a DeadlineConn, so that callers no longer need to repeat
+// the New(c).WithReadDeadline(...).WithWriteDeadline(...) dance at every
+// dial/accept site.
+type Options struct {
+	ReadDeadline  time.Duration // see WithReadDeadline.
+	WriteDeadline time.Duration // see WithWriteDeadline.
+	MinReadRate   int           // see WithMinReadRate, zero disables it.
+	MinWriteRate  int           // see WithMinWriteRate, zero disables it.
+	KeepAlive     time.Duration // see WithKeepAlive, zero (with a nil Probe) disables it.
+	Probe         Probe         // probe used by WithKeepAlive when KeepAlive > 0.
+	Hooks         Hooks         // see WithHooks.
+
+	// SetupConn, if non-nil, is called on the raw *net.TCPConn before it is
+	// wrapped, to tune socket options such as SetNoDelay/SetKeepAlive. It is
+	// skipped for non-TCP connections.
+	SetupConn func(*net.TCPConn) error
+}
+
+// wrap applies o to c, configuring the raw socket (if applicable) and
+// returning c wrapped in a DeadlineConn.
+func (o Options) wrap(c net.Conn) (net.Conn, error) {
+	if tc, ok := c.(*net.TCPConn); ok && o.SetupConn != nil {
+		if err := o.SetupConn(tc); err != nil {
+			return nil, err
+		}
+	}
+
+	dc := New(c).WithReadDeadline(o.ReadDeadline).WithWriteDeadline(o.WriteDeadline).WithHooks(o.Hooks)
+	if o.MinReadRate > 0 {
+		dc = dc.WithMinReadRate(o.MinReadRate)
+	}
+	if o.MinWriteRate > 0 {
+		dc = dc.WithMinWriteRate(o.MinWriteRate)
+	}
+	if o.KeepAlive > 0 && o.Probe != nil {
+		dc = dc.WithKeepAlive(o.KeepAlive, o.Probe)
+	}
+	return dc, nil
+}
+
+// listener wraps a net.Listener so every accepted connection comes back
+// pre-configured as a DeadlineConn.
+type listener struct {
+	net.Listener
+	opts Options
+}
+
+// Listener wraps inner so that every connection returned by Accept is a
+// DeadlineConn configured according to opts.
+func Listener(inner net.Listener, opts Options) net.Listener {
+	return &listener{Listener: inner, opts: opts}
+}
+
+// Accept waits for and returns the next connection, wrapped per l.opts.
+func (l *listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := l.opts.wrap(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return wrapped, nil
+}
+
+// Dialer mirrors net.Dialer, wrapping every connection it dials according to
+// Options. NetDialer is held as a named field rather than embedded, so that
+// none of net.Dialer's fields or methods are promoted onto Dialer; its only
+// dial methods are its own Dial/DialContext, which apply Options.
+type Dialer struct {
+	NetDialer net.Dialer // configures the underlying dial: Timeout, LocalAddr, etc.
+	Options   Options
+}
+
+// DialContext connects to addr on the named network and returns it wrapped
+// per d.Options, analogous to net.Dialer.DialContext.
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	c, err := d.NetDialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := d.Options.wrap(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return wrapped, nil
+}
+
+// Dial connects to addr on the named network and returns it wrapped per
+// d.Options, analogous to net.Dialer.Dial.
+func (d *Dialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext connects to addr on the named network using a zero-value
+// net.Dialer and returns it wrapped per opts, the package-level counterpart
+// of Dialer.DialContext.
+func DialContext(ctx context.Context, network, addr string, opts Options) (net.Conn, error) {
+	d := Dialer{Options: opts}
+	return d.DialContext(ctx, network, addr)
+}
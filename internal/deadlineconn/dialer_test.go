@@ -0,0 +1,101 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package deadlineconn
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestListenerWrapsAcceptedConns(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	wrapped := Listener(ln, Options{ReadDeadline: time.Second})
+
+	accepted := make(chan net.Conn, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		c, err := wrapped.Accept()
+		if err != nil {
+			errCh <- err
+			return
+		}
+		accepted <- c
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("Accept: %v", err)
+	case c := <-accepted:
+		defer c.Close()
+		if _, ok := c.(*DeadlineConn); !ok {
+			t.Fatalf("Accept returned %T, want *DeadlineConn", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Accept")
+	}
+}
+
+func TestDialerWrapsDialedConns(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	d := &Dialer{Options: Options{ReadDeadline: time.Second}}
+
+	conn, err := d.DialContext(context.Background(), "tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	defer conn.Close()
+	if _, ok := conn.(*DeadlineConn); !ok {
+		t.Fatalf("DialContext returned %T, want *DeadlineConn", conn)
+	}
+
+	conn2, err := d.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn2.Close()
+	if _, ok := conn2.(*DeadlineConn); !ok {
+		t.Fatalf("Dial returned %T, want *DeadlineConn", conn2)
+	}
+}